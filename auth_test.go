@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memStore is a minimal in-memory UserStore, good enough to exercise the
+// auth handlers without touching disk.
+type memStore struct {
+	mu     sync.Mutex
+	users  map[string]User
+	nextID int
+}
+
+func newMemStore() *memStore { return &memStore{users: map[string]User{}} }
+
+func (m *memStore) Get(ctx context.Context, id string) (User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[id]
+	if !ok {
+		return User{}, ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (m *memStore) List(ctx context.Context) ([]User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]User, 0, len(m.users))
+	for _, u := range m.users {
+		list = append(list, u)
+	}
+	return list, nil
+}
+
+func (m *memStore) Search(ctx context.Context, opts SearchOptions) (SearchResult, error) {
+	users, _ := m.List(ctx)
+	return searchInMemory(users, opts), nil
+}
+
+func (m *memStore) Create(ctx context.Context, user User) (User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	user.ID = strconv.Itoa(m.nextID)
+	m.users[user.ID] = user
+	return user, nil
+}
+
+func (m *memStore) Update(ctx context.Context, user User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[user.ID]; !ok {
+		return ErrUserNotFound
+	}
+	m.users[user.ID] = user
+	return nil
+}
+
+func (m *memStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[id]; !ok {
+		return ErrUserNotFound
+	}
+	delete(m.users, id)
+	return nil
+}
+
+func (m *memStore) Close() error { return nil }
+
+func TestNewTokenAndParseTokenRoundTrip(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	user := User{ID: "1", Role: RoleUser}
+	signed, err := newToken(user, "access", time.Minute)
+	if err != nil {
+		t.Fatalf("newToken: %v", err)
+	}
+
+	claims, err := parseToken(signed)
+	if err != nil {
+		t.Fatalf("parseToken: %v", err)
+	}
+	if claims.UserID != "1" || claims.Typ != "access" {
+		t.Fatalf("claims = %+v, unexpected value", claims)
+	}
+}
+
+func TestParseTokenRejectsExpired(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	signed, err := newToken(User{ID: "1"}, "access", -time.Minute)
+	if err != nil {
+		t.Fatalf("newToken: %v", err)
+	}
+	if _, err := parseToken(signed); err != ErrInvalidToken {
+		t.Fatalf("parseToken = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthCtxRejectsMissingOrWrongTypeToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	store := newMemStore()
+	api := &API{store: store}
+	user, err := store.Create(context.Background(), User{DisplayName: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	handler := api.AuthCtx(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("missing header", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/me", nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want 401", rec.Code)
+		}
+	})
+
+	t.Run("refresh token rejected as access token", func(t *testing.T) {
+		refresh, err := newToken(user, "refresh", time.Hour)
+		if err != nil {
+			t.Fatalf("newToken: %v", err)
+		}
+		req := httptest.NewRequest("GET", "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+refresh)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want 401", rec.Code)
+		}
+	})
+
+	t.Run("valid access token populates context", func(t *testing.T) {
+		access, err := newToken(user, "access", time.Hour)
+		if err != nil {
+			t.Fatalf("newToken: %v", err)
+		}
+		req := httptest.NewRequest("GET", "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+access)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d, want 200", rec.Code)
+		}
+	})
+}
+
+func TestRegisterLoginFlow(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	store := newMemStore()
+	api := &API{store: store}
+
+	registerBody := `{"display_name":"Ada Lovelace","email":"ada@example.com","password":"hunter22"}`
+	req := httptest.NewRequest("POST", "/register", strings.NewReader(registerBody))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	api.HandleRegister(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("HandleRegister status = %d, want 201, body = %s", rec.Code, rec.Body)
+	}
+	if strings.Contains(rec.Body.String(), "hunter22") || strings.Contains(rec.Body.String(), "password_hash") {
+		t.Fatalf("register response leaked password material: %s", rec.Body)
+	}
+
+	loginBody := `{"email":"ada@example.com","password":"hunter22"}`
+	req = httptest.NewRequest("POST", "/login", strings.NewReader(loginBody))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	api.HandleLogin(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("HandleLogin status = %d, want 200, body = %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), "access_token") || !strings.Contains(rec.Body.String(), "refresh_token") {
+		t.Fatalf("login response missing token pair: %s", rec.Body)
+	}
+
+	req = httptest.NewRequest("POST", "/login", strings.NewReader(`{"email":"ada@example.com","password":"wrong"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	api.HandleLogin(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("HandleLogin with wrong password status = %d, want 401", rec.Code)
+	}
+}
+
+func TestCanModify(t *testing.T) {
+	api := &API{}
+	owner := User{ID: "1", Role: RoleUser}
+	other := User{ID: "2", Role: RoleUser}
+	admin := User{ID: "3", Role: RoleAdmin}
+
+	asUser := func(u User) *http.Request {
+		ctx := context.WithValue(context.Background(), ctxKeyAuthUser, u)
+		return httptest.NewRequest("PATCH", "/users/1", nil).WithContext(ctx)
+	}
+
+	if !api.canModify(asUser(owner), owner) {
+		t.Error("canModify: owner should be able to modify their own record")
+	}
+	if api.canModify(asUser(other), owner) {
+		t.Error("canModify: a different user should not be able to modify owner's record")
+	}
+	if !api.canModify(asUser(admin), owner) {
+		t.Error("canModify: an admin should be able to modify any record")
+	}
+}
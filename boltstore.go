@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	usersBucket = []byte("users")
+	seqBucket   = []byte("seq")
+	seqKey      = []byte("user_id")
+)
+
+// BoltStore is a UserStore backed by a single bbolt file. Users are stored
+// JSON-encoded, keyed by their ID; a dedicated seq bucket holds the
+// monotonic counter used to mint new IDs, mirroring FileStore's Increment
+// field.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0644, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(usersBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(seqBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (bs *BoltStore) Get(ctx context.Context, id string) (User, error) {
+	var user storedUser
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(usersBucket).Get([]byte(id))
+		if data == nil {
+			return ErrUserNotFound
+		}
+		return json.Unmarshal(data, &user)
+	})
+	return user.toUser(), err
+}
+
+func (bs *BoltStore) List(ctx context.Context) ([]User, error) {
+	var users []User
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(usersBucket).ForEach(func(k, v []byte) error {
+			var user storedUser
+			if err := json.Unmarshal(v, &user); err != nil {
+				return err
+			}
+			users = append(users, user.toUser())
+			return nil
+		})
+	})
+	return users, err
+}
+
+func (bs *BoltStore) Search(ctx context.Context, opts SearchOptions) (SearchResult, error) {
+	users, err := bs.List(ctx)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	return searchInMemory(users, opts), nil
+}
+
+func (bs *BoltStore) Create(ctx context.Context, user User) (User, error) {
+	err := bs.db.Update(func(tx *bolt.Tx) error {
+		seq, err := tx.Bucket(seqBucket).NextSequence()
+		if err != nil {
+			return err
+		}
+		user.ID = strconv.FormatUint(seq, 10)
+
+		data, err := json.Marshal(toStoredUser(user))
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(usersBucket).Put([]byte(user.ID), data)
+	})
+	return user, err
+}
+
+func (bs *BoltStore) Update(ctx context.Context, user User) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(usersBucket)
+		if bucket.Get([]byte(user.ID)) == nil {
+			return ErrUserNotFound
+		}
+		data, err := json.Marshal(toStoredUser(user))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(user.ID), data)
+	})
+}
+
+func (bs *BoltStore) Delete(ctx context.Context, id string) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(usersBucket)
+		if bucket.Get([]byte(id)) == nil {
+			return ErrUserNotFound
+		}
+		return bucket.Delete([]byte(id))
+	})
+}
+
+func (bs *BoltStore) Close() error { return bs.db.Close() }
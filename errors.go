@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type ErrResponse struct {
+	Err            error `json:"-"`
+	HTTPStatusCode int   `json:"-"`
+
+	StatusText string            `json:"status"`
+	AppCode    int64             `json:"code,omitempty"`
+	ErrorText  string            `json:"error,omitempty"`
+	FieldErrs  map[string]string `json:"field_errors,omitempty"`
+}
+
+func (e *ErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, e.HTTPStatusCode)
+	return nil
+}
+
+// ErrInvalidRequest is for malformed requests that aren't field-validation
+// failures, e.g. a search query parameter that doesn't parse.
+func ErrInvalidRequest(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 400,
+		StatusText:     "Invalid request.",
+		ErrorText:      err.Error(),
+	}
+}
+
+// ErrValidation reports per-field validator failures. If err isn't a
+// validator.ValidationErrors (e.g. Bind failed before validation ran), it
+// falls back to a plain error message.
+func ErrValidation(err error) render.Renderer {
+	resp := &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 400,
+		StatusText:     "Validation failed.",
+		ErrorText:      err.Error(),
+	}
+
+	if verrs, ok := err.(validator.ValidationErrors); ok {
+		fields := make(map[string]string, len(verrs))
+		for _, fe := range verrs {
+			fields[fe.Field()] = fe.ActualTag()
+		}
+		resp.FieldErrs = fields
+	}
+	return resp
+}
+
+// ErrUnauthorized reports a missing, malformed or expired credential.
+func ErrUnauthorized(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 401,
+		StatusText:     "Unauthorized.",
+		ErrorText:      err.Error(),
+	}
+}
+
+// ErrForbidden reports an authenticated caller acting outside their
+// permissions, e.g. editing another user's record without the admin role.
+func ErrForbidden(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 403,
+		StatusText:     "Forbidden.",
+		ErrorText:      err.Error(),
+	}
+}
+
+// ErrNotFound reports a missing resource, e.g. ErrUserNotFound.
+func ErrNotFound(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 404,
+		StatusText:     "Resource not found.",
+		ErrorText:      err.Error(),
+	}
+}
+
+// renderStoreErr maps a UserStore error to the right HTTP response: a
+// missing-user sentinel becomes a 404, anything else is an opaque 500.
+func renderStoreErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, ErrUserNotFound) {
+		render.Render(w, r, ErrNotFound(err))
+		return
+	}
+	render.Render(w, r, ErrInternal(err))
+}
+
+// ErrInternal reports an unexpected server-side failure. The underlying
+// error is still attached for logging but ErrorText is kept generic so
+// internals (store DSNs, stack traces) never leak to clients.
+func ErrInternal(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Internal server error.",
+		ErrorText:      "internal server error",
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrValidationKeysFieldsByJSONName(t *testing.T) {
+	req := CreateUserRequest{DisplayName: "a", Email: "not-an-email"}
+	err := validate.Struct(&req)
+	if err == nil {
+		t.Fatal("validate.Struct: want error for short name and bad email, got nil")
+	}
+
+	resp, ok := ErrValidation(err).(*ErrResponse)
+	if !ok {
+		t.Fatalf("ErrValidation returned %T, want *ErrResponse", ErrValidation(err))
+	}
+
+	if _, ok := resp.FieldErrs["display_name"]; !ok {
+		t.Errorf("FieldErrs = %v, want a \"display_name\" key", resp.FieldErrs)
+	}
+	if _, ok := resp.FieldErrs["email"]; !ok {
+		t.Errorf("FieldErrs = %v, want an \"email\" key", resp.FieldErrs)
+	}
+	if _, ok := resp.FieldErrs["DisplayName"]; ok {
+		t.Errorf("FieldErrs = %v, should not be keyed by the Go field name", resp.FieldErrs)
+	}
+}
+
+func TestErrValidationFallsBackForNonValidatorErrors(t *testing.T) {
+	err := errors.New("body is not valid JSON")
+	resp, ok := ErrValidation(err).(*ErrResponse)
+	if !ok {
+		t.Fatalf("ErrValidation returned %T, want *ErrResponse", ErrValidation(err))
+	}
+	if resp.FieldErrs != nil {
+		t.Errorf("FieldErrs = %v, want nil for a non-validator error", resp.FieldErrs)
+	}
+	if resp.HTTPStatusCode != 400 {
+		t.Errorf("HTTPStatusCode = %d, want 400", resp.HTTPStatusCode)
+	}
+}
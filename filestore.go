@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// fileStoreData is the on-disk shape of a FileStore. List is keyed by user
+// ID, same as the original single-file implementation, but holds
+// storedUser (not User) so the password hash round-trips through save/load.
+type fileStoreData struct {
+	Increment int                   `json:"increment"`
+	List      map[string]storedUser `json:"list"`
+}
+
+// FileStore is the original JSON-file backed UserStore, now safe for
+// concurrent use: every call holds mu for the duration of the read-modify-write
+// and writes go through a temp file that's renamed into place so a crash or
+// concurrent reader never observes a half-written users.json.
+type FileStore struct {
+	mu   sync.RWMutex
+	path string
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	if path == "" {
+		path = store_path
+	}
+	fs := &FileStore{path: path}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := fs.save(&fileStoreData{List: map[string]storedUser{}}); err != nil {
+			return nil, err
+		}
+	}
+	return fs, nil
+}
+
+func (fs *FileStore) load() (*fileStoreData, error) {
+	data, err := os.ReadFile(fs.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var store fileStoreData
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, err
+	}
+	if store.List == nil {
+		store.List = map[string]storedUser{}
+	}
+	return &store, nil
+}
+
+func (fs *FileStore) save(store *fileStoreData) error {
+	data, err := json.Marshal(store)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fs.path), ".users-*.json.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, fs.path)
+}
+
+func (fs *FileStore) Get(ctx context.Context, id string) (User, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	store, err := fs.load()
+	if err != nil {
+		return User{}, err
+	}
+	user, found := store.List[id]
+	if !found {
+		return User{}, ErrUserNotFound
+	}
+	return user.toUser(), nil
+}
+
+func (fs *FileStore) List(ctx context.Context) ([]User, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	store, err := fs.load()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]User, 0, len(store.List))
+	for _, u := range store.List {
+		users = append(users, u.toUser())
+	}
+	return users, nil
+}
+
+func (fs *FileStore) Search(ctx context.Context, opts SearchOptions) (SearchResult, error) {
+	users, err := fs.List(ctx)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	return searchInMemory(users, opts), nil
+}
+
+func (fs *FileStore) Create(ctx context.Context, user User) (User, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	store, err := fs.load()
+	if err != nil {
+		return User{}, err
+	}
+
+	store.Increment++
+	user.ID = strconv.Itoa(store.Increment)
+	store.List[user.ID] = toStoredUser(user)
+
+	if err := fs.save(store); err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
+
+func (fs *FileStore) Update(ctx context.Context, user User) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	store, err := fs.load()
+	if err != nil {
+		return err
+	}
+	if _, found := store.List[user.ID]; !found {
+		return ErrUserNotFound
+	}
+	store.List[user.ID] = toStoredUser(user)
+	return fs.save(store)
+}
+
+func (fs *FileStore) Delete(ctx context.Context, id string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	store, err := fs.load()
+	if err != nil {
+		return err
+	}
+	if _, found := store.List[id]; !found {
+		return ErrUserNotFound
+	}
+	delete(store.List, id)
+	return fs.save(store)
+}
+
+func (fs *FileStore) Close() error { return nil }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// canModify reports whether the authenticated caller may modify target:
+// either they are target themselves, or they carry the admin role.
+func (a *API) canModify(r *http.Request, target User) bool {
+	caller, ok := GetAuthUser(r)
+	if !ok {
+		return false
+	}
+	return caller.ID == target.ID || caller.Role == RoleAdmin
+}
+
+func (a *API) HandleGetUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := a.GetUserCtx(r)
+	if !ok {
+		render.Render(w, r, ErrInternal(errors.New("UserCtx did not populate the request context")))
+		return
+	}
+	render.JSON(w, r, newUserResponse(user))
+}
+
+func (a *API) HandleSearchUsers(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseSearchOptions(r)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	result, err := a.store.Search(r.Context(), opts)
+	if err != nil {
+		renderStoreErr(w, r, err)
+		return
+	}
+
+	items := newUserResponseList(result.Items)
+	if items == nil {
+		items = []userResponse{}
+	}
+	resp := SearchResponse{Items: items, Total: result.Total}
+	if opts.Offset+len(result.Items) < result.Total {
+		resp.NextCursor = strconv.Itoa(opts.Offset + len(result.Items))
+	}
+	render.JSON(w, r, resp)
+}
+
+func (a *API) HandleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var request CreateUserRequest
+	if err := render.Bind(r, &request); err != nil {
+		render.Render(w, r, ErrValidation(err))
+		return
+	}
+
+	user := User{
+		CreatedAt:   time.Now(),
+		DisplayName: request.DisplayName,
+		Email:       request.Email,
+		Role:        RoleUser,
+	}
+
+	user, err := a.store.Create(r.Context(), user)
+	if err != nil {
+		renderStoreErr(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, map[string]interface{}{
+		"user_id": user.ID,
+	})
+}
+
+func (a *API) HandleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := a.GetUserCtx(r)
+	if !ok {
+		render.Render(w, r, ErrInternal(errors.New("UserCtx did not populate the request context")))
+		return
+	}
+	if !a.canModify(r, user) {
+		render.Render(w, r, ErrForbidden(errors.New("cannot modify another user's record")))
+		return
+	}
+
+	var request UpdateUserRequest
+	if err := render.Bind(r, &request); err != nil {
+		render.Render(w, r, ErrValidation(err))
+		return
+	}
+
+	user.DisplayName = request.DisplayName
+	if err := a.store.Update(r.Context(), user); err != nil {
+		renderStoreErr(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *API) HandleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := a.GetUserCtx(r)
+	if !ok {
+		render.Render(w, r, ErrInternal(errors.New("UserCtx did not populate the request context")))
+		return
+	}
+	if !a.canModify(r, user) {
+		render.Render(w, r, ErrForbidden(errors.New("cannot modify another user's record")))
+		return
+	}
+
+	if err := a.store.Delete(r.Context(), user.ID); err != nil {
+		renderStoreErr(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
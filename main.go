@@ -2,17 +2,19 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
+	"flag"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/render"
+
+	"golang_test/openapi"
 )
 
 const store_path = `users.json`
@@ -22,24 +24,37 @@ type User struct {
 	CreatedAt   time.Time `json:"created_at"`
 	DisplayName string    `json:"display_name"`
 	Email       string    `json:"email"`
+	Role        string    `json:"role,omitempty"`
+
+	// PasswordHash must never reach an API response, so it's tagged "-"
+	// rather than trusted to handlers to strip. Backends that persist a
+	// User via encoding/json (FileStore, BoltStore) go through storedUser
+	// instead, which does serialize it.
+	PasswordHash string `json:"-"`
 }
 
-type UserList map[string]User
+func main() {
+	storeURL := flag.String("store", "", "store backend URL, e.g. file://users.json, bolt://users.db, postgres://...")
+	flag.Parse()
 
-type UserStore struct {
-	Increment int      `json:"increment"`
-	List      UserList `json:"list"`
-}
+	if *storeURL == "" {
+		*storeURL = os.Getenv("STORE_URL")
+	}
+	if *storeURL == "" {
+		*storeURL = "file://" + store_path
+	}
 
-func (store *UserStore) Inc() {
-	store.Increment++
-}
+	mustJWTSecret()
+	mustTTLs()
 
-var (
-	ErrUserNotFound = errors.New("user_not_found")
-)
+	store, err := NewStore(*storeURL)
+	if err != nil {
+		log.Fatal("Error opening store: ", err)
+	}
+	defer store.Close()
+
+	api := &API{store: store}
 
-func main() {
 	r := chi.NewRouter()
 
 	r.Use(
@@ -51,193 +66,103 @@ func main() {
 	)
 
 	r.Get("/", handleHome)
+	r.Get("/openapi.yaml", handleOpenAPISpec)
+	r.Get("/docs", http.RedirectHandler("/docs/", http.StatusMovedPermanently).ServeHTTP)
+	r.Handle("/docs/*", http.StripPrefix("/docs/", http.FileServer(http.FS(docsFS()))))
+	r.Route("/api/v1/auth", func(r chi.Router) {
+		r.Post("/register", api.HandleRegister)
+		r.Post("/login", api.HandleLogin)
+		r.Post("/refresh", api.HandleRefresh)
+		r.With(api.AuthCtx).Get("/me", api.HandleMe)
+	})
 	r.Route("/api/v1/users", func(r chi.Router) {
-		r.Get("/", HandleSearchUsers)
-		r.Post("/", HandleCreateUser)
+		r.Get("/", api.HandleSearchUsers)
+		r.Post("/", api.HandleCreateUser)
 		r.Route("/{id}", func(r chi.Router) {
-			r.Use(UserCtx)
-			r.Get("/", HandleGetUser)
-			r.Patch("/", HandleUpdateUser)
-			r.Delete("/", HandleDeleteUser)
+			r.Use(api.UserCtx)
+			r.Get("/", api.HandleGetUser)
+			r.With(api.AuthCtx).Patch("/", api.HandleUpdateUser)
+			r.With(api.AuthCtx).Delete("/", api.HandleDeleteUser)
 		})
 	})
-	err := http.ListenAndServe(":3333", r)
-	if err != nil {
-		log.Fatal("Error starting server: ", err)
-	}
-}
 
-func handleHome(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte(time.Now().String()))
-}
+	srv := &http.Server{Addr: ":3333", Handler: r}
 
-func HandleGetUser(w http.ResponseWriter, r *http.Request) {
-	user := GetUserCtx(r)
-	render.JSON(w, r, user)
-}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-func ReadUserStore() (*UserStore, error) { //NOTE: New function
-	data, err := os.ReadFile(store_path)
-	if err != nil {
-		return nil, err
-	}
-
-	var store UserStore
-	err = json.Unmarshal(data, &store)
-	if err != nil {
-		return nil, err
-	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Error starting server: ", err)
+		}
+	}()
 
-	return &store, nil
-}
+	<-ctx.Done()
+	stop()
+	log.Print("Shutting down: waiting for in-flight requests to finish")
 
-func SaveUserStore(store *UserStore) error { // NOTE: new function
-	data, err := json.Marshal(store)
-	if err != nil {
-		return err
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Fatal("Error during shutdown: ", err)
 	}
-
-	err = os.WriteFile(store_path, data, 0644)
-	return err
 }
 
-func HandleSearchUsers(w http.ResponseWriter, r *http.Request) {
-	store, err := ReadUserStore()
-	if err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
-		return
-	}
-	render.JSON(w, r, store.List)
+func handleHome(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(time.Now().String()))
 }
 
-func HandleCreateUser(w http.ResponseWriter, r *http.Request) {
-	store, err := ReadUserStore()
+func handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	spec, err := openapi.Spec()
 	if err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
-		return
-	}
-
-	var request CreateUserRequest
-	if err := render.Bind(r, &request); err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
+		renderStoreErr(w, r, err)
 		return
 	}
-
-	store.Inc()
-	id := strconv.Itoa(store.Increment)
-	user := User{
-		ID:          id,
-		CreatedAt:   time.Now(),
-		DisplayName: request.DisplayName,
-		Email:       request.Email,
-	}
-
-	store.List[id] = user
-	if err := SaveUserStore(store); err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
-		return
-	}
-
-	render.Status(r, http.StatusCreated)
-	render.JSON(w, r, map[string]interface{}{
-		"user_id": id,
-	})
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(spec)
 }
 
-func HandleUpdateUser(w http.ResponseWriter, r *http.Request) {
-	store, err := ReadUserStore()
+// docsFS roots the embedded Swagger UI page at "/" instead of "/docs" so
+// http.FileServer can serve it directly off the /docs/ route.
+func docsFS() fs.FS {
+	sub, err := fs.Sub(openapi.DocsFS, "docs")
 	if err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
-		return
+		log.Fatal("Error mounting embedded docs: ", err)
 	}
+	return sub
+}
 
-	user := GetUserCtx(r)
-	var request UpdateUserRequest
-	if err := render.Bind(r, &request); err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
-		return
-	}
-
-	user.DisplayName = request.DisplayName
-	store.List[user.ID] = user
-
-	if err := SaveUserStore(store); err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
-		return
-	}
-	render.Status(r, http.StatusNoContent)
+// API holds the dependencies every handler needs. Handlers are methods on
+// it rather than free functions so tests can substitute an in-memory
+// UserStore instead of hitting the real backend.
+type API struct {
+	store UserStore
 }
 
-func HandleDeleteUser(w http.ResponseWriter, r *http.Request) {
-	store, err := ReadUserStore()
-	if err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
-		return
-	}
-	user := GetUserCtx(r)
-	delete(store.List, user.ID)
+// ctxKey is the type every context value this package sets is keyed by, so
+// a key here can never collide with one set by another package. ctxKeyUser
+// and ctxKeyAuthUser (auth.go) share it.
+type ctxKey int
 
-	if err := SaveUserStore(store); err != nil {
-		render.Render(w, r, ErrInvalidRequest(err))
-		return
-	}
-	render.Status(r, http.StatusNoContent)
-}
+const ctxKeyUser ctxKey = iota
 
-func GetUserCtx(r *http.Request) User {
-	return r.Context().Value("user").(User)
+// GetUserCtx returns the :id route's user loaded by UserCtx. The bool is
+// false if UserCtx was never run (a handler wired without it), so callers
+// can respond with a 500 instead of panicking on a bad type assertion.
+func (a *API) GetUserCtx(r *http.Request) (User, bool) {
+	user, ok := r.Context().Value(ctxKeyUser).(User)
+	return user, ok
 }
 
-func UserCtx(next http.Handler) http.Handler {
+func (a *API) UserCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		userID := chi.URLParam(r, "id")
-		store, err := ReadUserStore()
+		user, err := a.store.Get(r.Context(), userID)
 		if err != nil {
-			render.Render(w, r, ErrInvalidRequest(err))
-			return
-		}
-
-		user, found := store.List[userID]
-		if !found {
-			render.Render(w, r, ErrInvalidRequest(ErrUserNotFound))
+			renderStoreErr(w, r, err)
 			return
 		}
-		ctx := context.WithValue(r.Context(), "user", user)
+		ctx := context.WithValue(r.Context(), ctxKeyUser, user)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
-
-type CreateUserRequest struct {
-	DisplayName string `json:"display_name"`
-	Email       string `json:"email"`
-}
-
-func (c *CreateUserRequest) Bind(r *http.Request) error { return nil }
-func (u *UpdateUserRequest) Bind(r *http.Request) error { return nil }
-
-type UpdateUserRequest struct {
-	DisplayName string `json:"display_name"`
-}
-
-type ErrResponse struct {
-	Err            error `json:"-"`
-	HTTPStatusCode int   `json:"-"`
-
-	StatusText string `json:"status"`
-	AppCode    int64  `json:"code,omitempty"`
-	ErrorText  string `json:"error,omitempty"`
-}
-
-func (e *ErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
-	render.Status(r, e.HTTPStatusCode)
-	return nil
-}
-
-func ErrInvalidRequest(err error) render.Renderer {
-	return &ErrResponse{
-		Err:            err,
-		HTTPStatusCode: 400,
-		StatusText:     "Invalid request.",
-		ErrorText:      err.Error(),
-	}
-}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestGetUserCtxFalseWhenUserCtxNeverRan(t *testing.T) {
+	api := &API{}
+	req := httptest.NewRequest("GET", "/users/1", nil)
+
+	user, ok := api.GetUserCtx(req)
+	if ok {
+		t.Fatalf("GetUserCtx: ok = true, want false for a request UserCtx never touched (got %+v)", user)
+	}
+}
+
+func TestUserCtxPopulatesContextForDownstreamHandler(t *testing.T) {
+	store := newMemStore()
+	api := &API{store: store}
+	created, err := store.Create(context.Background(), User{DisplayName: "Ada", Email: "ada@example.com"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	var gotUser User
+	var gotOK bool
+	r := chi.NewRouter()
+	r.Route("/users/{id}", func(r chi.Router) {
+		r.Use(api.UserCtx)
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			gotUser, gotOK = api.GetUserCtx(r)
+			w.WriteHeader(http.StatusOK)
+		})
+	})
+
+	req := httptest.NewRequest("GET", "/users/"+created.ID, nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if !gotOK {
+		t.Fatal("GetUserCtx: ok = false, want true after UserCtx ran")
+	}
+	if gotUser.ID != created.ID {
+		t.Errorf("GetUserCtx user ID = %q, want %q", gotUser.ID, created.ID)
+	}
+}
+
+func TestUserCtxRespondsNotFoundForUnknownID(t *testing.T) {
+	store := newMemStore()
+	api := &API{store: store}
+
+	r := chi.NewRouter()
+	r.Route("/users/{id}", func(r chi.Router) {
+		r.Use(api.UserCtx)
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler should not run when UserCtx fails to load the user")
+		})
+	})
+
+	req := httptest.NewRequest("GET", "/users/missing", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
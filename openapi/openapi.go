@@ -0,0 +1,16 @@
+// Package openapi embeds the service's OpenAPI 3 spec and a Swagger UI page
+// so the API contract is browsable without a separate doc build step.
+package openapi
+
+import "embed"
+
+//go:embed openapi.yaml
+var specFS embed.FS
+
+//go:embed docs
+var DocsFS embed.FS
+
+// Spec returns the raw openapi.yaml contents.
+func Spec() ([]byte, error) {
+	return specFS.ReadFile("openapi.yaml")
+}
@@ -0,0 +1,27 @@
+package openapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// TestSpecIsValid parses and validates openapi.yaml; `make check-openapi`
+// runs it. It checks that the spec is well-formed OpenAPI 3; it does not
+// compare the spec against the handlers' actual routes or payloads.
+func TestSpecIsValid(t *testing.T) {
+	data, err := Spec()
+	if err != nil {
+		t.Fatalf("read spec: %v", err)
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		t.Fatalf("parse spec: %v", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("validate spec: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+func init() {
+	// Field errors are keyed by the wire name (e.g. "display_name"), not the
+	// Go struct field name, so ErrValidation's response matches the request
+	// body the caller actually sent.
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+}
+
+type CreateUserRequest struct {
+	DisplayName string `json:"display_name" validate:"required,min=2,max=64"`
+	Email       string `json:"email" validate:"required,email"`
+}
+
+func (c *CreateUserRequest) Bind(r *http.Request) error {
+	return validate.Struct(c)
+}
+
+type UpdateUserRequest struct {
+	DisplayName string `json:"display_name" validate:"required,min=2,max=64"`
+}
+
+func (u *UpdateUserRequest) Bind(r *http.Request) error {
+	return validate.Struct(u)
+}
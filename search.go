@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 1000
+)
+
+// SearchResponse is the JSON envelope returned by HandleSearchUsers.
+type SearchResponse struct {
+	Items      []userResponse `json:"items"`
+	Total      int            `json:"total"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// parseSearchOptions turns the query string of a search request into
+// SearchOptions, rejecting anything that doesn't parse as invalid rather
+// than silently falling back to a default.
+func parseSearchOptions(r *http.Request) (SearchOptions, error) {
+	q := r.URL.Query()
+	opts := SearchOptions{
+		Query:     q.Get("q"),
+		Limit:     defaultSearchLimit,
+		SortOrder: SortAsc,
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return SearchOptions{}, errors.New("limit must be a positive integer")
+		}
+		if limit > maxSearchLimit {
+			return SearchOptions{}, errors.New("limit exceeds maximum of 1000")
+		}
+		opts.Limit = limit
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return SearchOptions{}, errors.New("cursor is invalid")
+		}
+		opts.Offset = offset
+	} else if raw := q.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return SearchOptions{}, errors.New("offset must be a non-negative integer")
+		}
+		opts.Offset = offset
+	}
+
+	if raw := q.Get("sort_column"); raw != "" {
+		switch SortColumn(raw) {
+		case SortByCreatedAt, SortByDisplayName, SortByEmail:
+			opts.SortColumn = SortColumn(raw)
+		default:
+			return SearchOptions{}, errors.New("sort_column must be one of created_at, display_name, email")
+		}
+	}
+
+	if raw := q.Get("sort_order"); raw != "" {
+		switch SortOrder(raw) {
+		case SortAsc, SortDesc:
+			opts.SortOrder = SortOrder(raw)
+		default:
+			return SearchOptions{}, errors.New("sort_order must be asc or desc")
+		}
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseSearchOptionsDefaults(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users", nil)
+	opts, err := parseSearchOptions(r)
+	if err != nil {
+		t.Fatalf("parseSearchOptions: %v", err)
+	}
+	if opts.Limit != defaultSearchLimit {
+		t.Errorf("Limit = %d, want %d", opts.Limit, defaultSearchLimit)
+	}
+	if opts.SortOrder != SortAsc {
+		t.Errorf("SortOrder = %q, want %q", opts.SortOrder, SortAsc)
+	}
+	if opts.Offset != 0 {
+		t.Errorf("Offset = %d, want 0", opts.Offset)
+	}
+}
+
+func TestParseSearchOptionsValid(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users?q=ada&limit=5&offset=10&sort_column=email&sort_order=desc", nil)
+	opts, err := parseSearchOptions(r)
+	if err != nil {
+		t.Fatalf("parseSearchOptions: %v", err)
+	}
+	if opts.Query != "ada" || opts.Limit != 5 || opts.Offset != 10 ||
+		opts.SortColumn != SortByEmail || opts.SortOrder != SortDesc {
+		t.Fatalf("opts = %+v, unexpected value", opts)
+	}
+}
+
+func TestParseSearchOptionsCursorOverridesOffset(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users?cursor=7&offset=1", nil)
+	opts, err := parseSearchOptions(r)
+	if err != nil {
+		t.Fatalf("parseSearchOptions: %v", err)
+	}
+	if opts.Offset != 7 {
+		t.Errorf("Offset = %d, want 7 (cursor should win over offset)", opts.Offset)
+	}
+}
+
+func TestParseSearchOptionsRejectsInvalidInput(t *testing.T) {
+	cases := []string{
+		"/users?limit=0",
+		"/users?limit=-1",
+		"/users?limit=abc",
+		"/users?limit=1001",
+		"/users?offset=-1",
+		"/users?cursor=-1",
+		"/users?sort_column=bogus",
+		"/users?sort_order=bogus",
+	}
+
+	for _, target := range cases {
+		r := httptest.NewRequest("GET", target, nil)
+		if _, err := parseSearchOptions(r); err == nil {
+			t.Errorf("parseSearchOptions(%q): want error, got nil", target)
+		}
+	}
+}
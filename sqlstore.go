@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// migrations runs in order against a freshly opened database. It's
+// intentionally simple (no external migration tool) since the schema is a
+// single table; reach for golang-migrate if it ever grows past this.
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS users (
+		id           TEXT PRIMARY KEY,
+		display_name TEXT NOT NULL,
+		email        TEXT NOT NULL,
+		created_at   TIMESTAMP NOT NULL
+	)`,
+	`ALTER TABLE users ADD COLUMN password_hash TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user'`,
+}
+
+// SQLStore is a UserStore backed by database/sql. It pushes Search's
+// filtering, sorting and pagination down into the query instead of loading
+// every row into memory.
+type SQLStore struct {
+	db     *sql.DB
+	driver string
+}
+
+// rebind rewrites ?-style placeholders into whatever syntax s.driver
+// expects. database/sql itself has no generic placeholder: Postgres only
+// accepts $1, $2, ...; MySQL and SQLite accept ? directly.
+func (s *SQLStore) rebind(query string) string {
+	if s.driver != "postgres" && s.driver != "postgresql" {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// likeEscaper escapes LIKE's wildcard characters (and the escape character
+// itself) so a Query containing a literal "%" or "_" matches the same
+// substring strings.Contains would — the semantics searchInMemory gives
+// the file/bolt backends — instead of being interpreted as a SQL wildcard.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string { return likeEscaper.Replace(s) }
+
+// NewSQLStore opens driver with dsn and applies migrations. Only "postgres"
+// is wired up today (github.com/lib/pq is blank-imported above); add
+// another driver's blank import and a rebind case to support it.
+func NewSQLStore(driver, dsn string) (*SQLStore, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sqlstore: open: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlstore: ping: %w", err)
+	}
+
+	store := &SQLStore{db: db, driver: driver}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// migrate applies each migration exactly once, tracked by index in
+// schema_migrations, so re-running it on an already-migrated database
+// (e.g. every process start) is a no-op.
+func (s *SQLStore) migrate() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return fmt.Errorf("sqlstore: create schema_migrations: %w", err)
+	}
+
+	for i, stmt := range migrations {
+		var applied int
+		err := s.db.QueryRow(s.rebind(`SELECT count(*) FROM schema_migrations WHERE version = ?`), i).Scan(&applied)
+		if err != nil {
+			return fmt.Errorf("sqlstore: check migration %d: %w", i, err)
+		}
+		if applied > 0 {
+			continue
+		}
+
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("sqlstore: migration %d: %w", i, err)
+		}
+		if _, err := s.db.Exec(s.rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), i); err != nil {
+			return fmt.Errorf("sqlstore: record migration %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore) Get(ctx context.Context, id string) (User, error) {
+	var user User
+	row := s.db.QueryRowContext(ctx,
+		s.rebind(`SELECT id, display_name, email, created_at, password_hash, role FROM users WHERE id = ?`), id)
+	err := row.Scan(&user.ID, &user.DisplayName, &user.Email, &user.CreatedAt, &user.PasswordHash, &user.Role)
+	if err == sql.ErrNoRows {
+		return User{}, ErrUserNotFound
+	}
+	return user, err
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]User, error) {
+	rows, err := s.db.QueryContext(ctx,
+		s.rebind(`SELECT id, display_name, email, created_at, password_hash, role FROM users`))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUsers(rows)
+}
+
+func (s *SQLStore) Search(ctx context.Context, opts SearchOptions) (SearchResult, error) {
+	column := "created_at"
+	switch opts.SortColumn {
+	case SortByDisplayName:
+		column = "display_name"
+	case SortByEmail:
+		column = "email"
+	}
+	order := "ASC"
+	if opts.SortOrder == SortDesc {
+		order = "DESC"
+	}
+
+	where := ""
+	args := []interface{}{}
+	if opts.Query != "" {
+		where = `WHERE LOWER(display_name) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'`
+		like := "%" + escapeLike(strings.ToLower(opts.Query)) + "%"
+		args = append(args, like, like)
+	}
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT count(*) FROM users %s`, where)
+	if err := s.db.QueryRowContext(ctx, s.rebind(countQuery), args...).Scan(&total); err != nil {
+		return SearchResult{}, err
+	}
+
+	query := fmt.Sprintf(
+		`SELECT id, display_name, email, created_at, password_hash, role FROM users %s ORDER BY %s %s LIMIT ? OFFSET ?`,
+		where, column, order)
+	args = append(args, opts.Limit, opts.Offset)
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	defer rows.Close()
+
+	items, err := scanUsers(rows)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	return SearchResult{Items: items, Total: total}, nil
+}
+
+func (s *SQLStore) Create(ctx context.Context, user User) (User, error) {
+	if user.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return User{}, err
+		}
+		user.ID = id
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		s.rebind(`INSERT INTO users (id, display_name, email, created_at, password_hash, role) VALUES (?, ?, ?, ?, ?, ?)`),
+		user.ID, user.DisplayName, user.Email, user.CreatedAt, user.PasswordHash, user.Role)
+	return user, err
+}
+
+// newID mints a random 128-bit hex ID, used by backends (SQL, ...) that
+// don't have a natural monotonic sequence to key rows on.
+func newID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", b), nil
+}
+
+func (s *SQLStore) Update(ctx context.Context, user User) error {
+	// PasswordHash and Role are set when the user is created; this profile
+	// update never changes them.
+	result, err := s.db.ExecContext(ctx,
+		s.rebind(`UPDATE users SET display_name = ?, email = ? WHERE id = ?`),
+		user.DisplayName, user.Email, user.ID)
+	if err != nil {
+		return err
+	}
+	return requireRowAffected(result)
+}
+
+func (s *SQLStore) Delete(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM users WHERE id = ?`), id)
+	if err != nil {
+		return err
+	}
+	return requireRowAffected(result)
+}
+
+func (s *SQLStore) Close() error { return s.db.Close() }
+
+func requireRowAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func scanUsers(rows *sql.Rows) ([]User, error) {
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.DisplayName, &user.Email, &user.CreatedAt, &user.PasswordHash, &user.Role); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
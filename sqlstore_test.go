@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestEscapeLikeEscapesWildcards(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"ada", "ada"},
+		{"50% off", `50\% off`},
+		{"a_b", `a\_b`},
+		{`a\b`, `a\\b`},
+	}
+
+	for _, c := range cases {
+		if got := escapeLike(c.in); got != c.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
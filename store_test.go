@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewStoreDispatchesByScheme(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("file scheme", func(t *testing.T) {
+		path := filepath.Join(dir, "users.json")
+		store, err := NewStore("file://" + path)
+		if err != nil {
+			t.Fatalf("NewStore: %v", err)
+		}
+		defer store.Close()
+		if _, ok := store.(*FileStore); !ok {
+			t.Fatalf("NewStore: got %T, want *FileStore", store)
+		}
+	})
+
+	t.Run("bolt scheme", func(t *testing.T) {
+		path := filepath.Join(dir, "users.db")
+		store, err := NewStore("bolt://" + path)
+		if err != nil {
+			t.Fatalf("NewStore: %v", err)
+		}
+		defer store.Close()
+		if _, ok := store.(*BoltStore); !ok {
+			t.Fatalf("NewStore: got %T, want *BoltStore", store)
+		}
+	})
+
+	t.Run("bolt scheme with no path errors", func(t *testing.T) {
+		if _, err := NewStore("bolt://"); err == nil {
+			t.Fatal("NewStore: want error for bolt URL with no path, got nil")
+		}
+	})
+
+	t.Run("unsupported scheme errors", func(t *testing.T) {
+		if _, err := NewStore("redis://localhost"); err == nil {
+			t.Fatal("NewStore: want error for unsupported scheme, got nil")
+		}
+	})
+}
+
+func TestFilePathFromURLHandlesHostAsFirstPathSegment(t *testing.T) {
+	cases := []struct {
+		rawURL string
+		want   string
+	}{
+		{"file://users.json", "users.json"},
+		{"file:///tmp/users.json", "/tmp/users.json"},
+		{"bolt://data/users.db", "data/users.db"},
+		{"file:users.json", "users.json"},
+	}
+
+	for _, c := range cases {
+		u, err := url.Parse(c.rawURL)
+		if err != nil {
+			t.Fatalf("parse %q: %v", c.rawURL, err)
+		}
+		if got := filePathFromURL(u); got != c.want {
+			t.Errorf("filePathFromURL(%q) = %q, want %q", c.rawURL, got, c.want)
+		}
+	}
+}
+
+func TestSearchInMemoryFiltersSortsAndPaginates(t *testing.T) {
+	users := []User{
+		{ID: "1", DisplayName: "Charlie", Email: "charlie@example.com"},
+		{ID: "2", DisplayName: "Alice", Email: "alice@example.com"},
+		{ID: "3", DisplayName: "Bob", Email: "bob@example.com"},
+	}
+
+	result := searchInMemory(users, SearchOptions{
+		SortColumn: SortByDisplayName,
+		SortOrder:  SortAsc,
+		Limit:      2,
+	})
+	if result.Total != 3 {
+		t.Fatalf("Total = %d, want 3", result.Total)
+	}
+	if len(result.Items) != 2 || result.Items[0].DisplayName != "Alice" || result.Items[1].DisplayName != "Bob" {
+		t.Fatalf("Items = %+v, want [Alice Bob]", result.Items)
+	}
+
+	result = searchInMemory(users, SearchOptions{Query: "charlie"})
+	if len(result.Items) != 1 || result.Items[0].ID != "1" {
+		t.Fatalf("Query filter: Items = %+v, want just Charlie", result.Items)
+	}
+}
+
+func TestFileStoreRoundTripsPasswordHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	created, err := store.Create(ctx, User{DisplayName: "Ada", Email: "ada@example.com", PasswordHash: "hash"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := store.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.PasswordHash != "hash" {
+		t.Fatalf("PasswordHash = %q, want %q (lost across save/load)", got.PasswordHash, "hash")
+	}
+}
+
+func TestBoltStoreRoundTripsPasswordHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.db")
+	store, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltStore: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	created, err := store.Create(ctx, User{DisplayName: "Ada", Email: "ada@example.com", PasswordHash: "hash"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := store.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.PasswordHash != "hash" {
+		t.Fatalf("PasswordHash = %q, want %q (lost across save/load)", got.PasswordHash, "hash")
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import "time"
+
+// userResponse is the public JSON shape of a User: everything but the
+// password hash, which the store needs to persist but no response may ever
+// include.
+type userResponse struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	DisplayName string    `json:"display_name"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role,omitempty"`
+}
+
+func newUserResponse(u User) userResponse {
+	return userResponse{
+		ID:          u.ID,
+		CreatedAt:   u.CreatedAt,
+		DisplayName: u.DisplayName,
+		Email:       u.Email,
+		Role:        u.Role,
+	}
+}
+
+func newUserResponseList(users []User) []userResponse {
+	list := make([]userResponse, len(users))
+	for i, u := range users {
+		list[i] = newUserResponse(u)
+	}
+	return list
+}